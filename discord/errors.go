@@ -0,0 +1,117 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Sentinel errors identifying the REST failure categories resources branch
+// on. Check for these with errors.Is rather than comparing
+// *discordgo.RESTError status codes by hand - executeWithRetry classifies
+// the REST errors it returns into a *DiscordError wrapping one of these where
+// it recognizes the status code. The error it returns once MaxElapsedTime
+// would be exceeded wraps only ErrMaxElapsedTime.
+var (
+	ErrRateLimited        = errors.New("discord: rate limited")
+	ErrGlobalRateLimit    = errors.New("discord: global rate limit")
+	ErrNotFound           = errors.New("discord: resource not found")
+	ErrForbidden          = errors.New("discord: forbidden")
+	ErrMaxRetriesExceeded = errors.New("discord: max retries exceeded")
+)
+
+// DiscordError wraps a *discordgo.RESTError with the semantic category
+// callers care about, so they can branch with errors.Is/errors.As instead
+// of type-asserting *discordgo.RESTError and re-deriving the status code
+// themselves.
+type DiscordError struct {
+	// Err is the matching sentinel (ErrRateLimited, ErrGlobalRateLimit,
+	// ErrNotFound, ErrForbidden), or nil if the status code isn't one
+	// DiscordError specially classifies.
+	Err        error
+	REST       *discordgo.RESTError
+	Code       int
+	HTTPStatus int
+	Bucket     string
+	RetryAfter time.Duration
+}
+
+func (e *DiscordError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s (http %d, code %d)", e.Err, e.HTTPStatus, e.Code)
+	}
+	return fmt.Sprintf("discord api error (http %d, code %d)", e.HTTPStatus, e.Code)
+}
+
+// Unwrap exposes the underlying *discordgo.RESTError, so callers that still
+// want the raw response can get it with errors.As.
+func (e *DiscordError) Unwrap() error {
+	return e.REST
+}
+
+// Is reports whether target is this error's sentinel category.
+func (e *DiscordError) Is(target error) bool {
+	return e.Err != nil && e.Err == target
+}
+
+// classify wraps a raw error from a discordgo call into a *DiscordError
+// when it recognizes a REST error with a well-known status code. Errors it
+// doesn't recognize - context cancellation, non-Discord transport errors,
+// REST errors with an unclassified status - pass through unchanged.
+func classify(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var restErr *discordgo.RESTError
+	if !errors.As(err, &restErr) || restErr.Response == nil {
+		return err
+	}
+
+	de := &DiscordError{
+		REST:       restErr,
+		HTTPStatus: restErr.Response.StatusCode,
+		Bucket:     restErr.Response.Header.Get("X-RateLimit-Bucket"),
+		RetryAfter: parseRetryAfter(restErr),
+	}
+	if restErr.Message != nil {
+		de.Code = restErr.Message.Code
+	}
+
+	switch {
+	case de.HTTPStatus == http.StatusTooManyRequests && restErr.Response.Header.Get("X-RateLimit-Global") == "true":
+		de.Err = ErrGlobalRateLimit
+	case de.HTTPStatus == http.StatusTooManyRequests:
+		de.Err = ErrRateLimited
+	case de.HTTPStatus == http.StatusNotFound:
+		de.Err = ErrNotFound
+	case de.HTTPStatus == http.StatusForbidden:
+		de.Err = ErrForbidden
+	}
+
+	return de
+}
+
+// retriesExhaustedError wraps the error from the last failed attempt once
+// executeWithRetry gives up, so callers can check errors.Is(err,
+// ErrMaxRetriesExceeded) while errors.As still reaches the underlying
+// *DiscordError/*discordgo.RESTError via Unwrap.
+type retriesExhaustedError struct {
+	attempts int
+	err      error
+}
+
+func (e *retriesExhaustedError) Error() string {
+	return fmt.Sprintf("max retries (%d) exceeded: %s", e.attempts, e.err)
+}
+
+func (e *retriesExhaustedError) Unwrap() error {
+	return e.err
+}
+
+func (e *retriesExhaustedError) Is(target error) bool {
+	return target == ErrMaxRetriesExceeded
+}
@@ -0,0 +1,107 @@
+package discord
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestClassify_NotFound(t *testing.T) {
+	err := classify(&discordgo.RESTError{
+		Response: &http.Response{StatusCode: http.StatusNotFound, Header: http.Header{}},
+	})
+
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestClassify_Forbidden(t *testing.T) {
+	err := classify(&discordgo.RESTError{
+		Response: &http.Response{StatusCode: http.StatusForbidden, Header: http.Header{}},
+	})
+
+	if !errors.Is(err, ErrForbidden) {
+		t.Errorf("expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestClassify_RateLimited(t *testing.T) {
+	err := classify(&discordgo.RESTError{
+		Response: &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}},
+	})
+
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited, got %v", err)
+	}
+	if errors.Is(err, ErrGlobalRateLimit) {
+		t.Error("expected a bucket 429 to not also match ErrGlobalRateLimit")
+	}
+}
+
+func TestClassify_GlobalRateLimited(t *testing.T) {
+	err := classify(&discordgo.RESTError{
+		Response: &http.Response{
+			StatusCode: http.StatusTooManyRequests,
+			Header:     http.Header{"X-RateLimit-Global": []string{"true"}},
+		},
+	})
+
+	if !errors.Is(err, ErrGlobalRateLimit) {
+		t.Errorf("expected ErrGlobalRateLimit, got %v", err)
+	}
+}
+
+func TestClassify_UnwrapsToRESTError(t *testing.T) {
+	restErr := &discordgo.RESTError{
+		Response: &http.Response{StatusCode: http.StatusNotFound, Header: http.Header{}},
+	}
+
+	var got *discordgo.RESTError
+	if !errors.As(classify(restErr), &got) {
+		t.Fatal("expected errors.As to reach the underlying *discordgo.RESTError")
+	}
+	if got != restErr {
+		t.Error("expected the unwrapped error to be the original RESTError")
+	}
+}
+
+func TestClassify_PassesThroughNonRESTErrors(t *testing.T) {
+	original := errors.New("network blip")
+	if classify(original) != original {
+		t.Error("expected a non-REST error to pass through unchanged")
+	}
+}
+
+func TestClassify_PassesThroughUnclassifiedStatus(t *testing.T) {
+	err := classify(&discordgo.RESTError{
+		Response: &http.Response{StatusCode: http.StatusInternalServerError, Header: http.Header{}},
+	})
+
+	var de *DiscordError
+	if !errors.As(err, &de) {
+		t.Fatal("expected a *DiscordError even without a matching sentinel")
+	}
+	if de.Err != nil {
+		t.Errorf("expected no sentinel category for a 500, got %v", de.Err)
+	}
+	if de.HTTPStatus != http.StatusInternalServerError {
+		t.Errorf("expected HTTPStatus 500, got %d", de.HTTPStatus)
+	}
+}
+
+func TestRetriesExhaustedError_IsAndUnwrap(t *testing.T) {
+	inner := classify(&discordgo.RESTError{
+		Response: &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}},
+	})
+	err := &retriesExhaustedError{attempts: 5, err: inner}
+
+	if !errors.Is(err, ErrMaxRetriesExceeded) {
+		t.Error("expected errors.Is to match ErrMaxRetriesExceeded")
+	}
+	if !errors.Is(err, ErrRateLimited) {
+		t.Error("expected errors.Is to reach the wrapped ErrRateLimited via Unwrap")
+	}
+}
@@ -0,0 +1,89 @@
+package discord
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// idempotencyWindow bounds how far back executeIdempotent's lookup looks
+// for a resource that already satisfies a create call, so a Terraform
+// apply that crashed mid-retry recovers the resource a prior attempt
+// already created instead of producing a duplicate.
+const idempotencyWindow = 10 * time.Minute
+
+// idempotencyKeyAttr is the Computed state attribute name create functions
+// use to persist the idempotency key they derived, so it survives in state
+// for diagnostics even though Discord has no concept of it.
+const idempotencyKeyAttr = "idempotency_key"
+
+// idempotencyLookup checks whether a resource matching a pending create
+// call already exists - typically left over from an earlier, crashed
+// attempt - and if so returns its ID and creation time.
+type idempotencyLookup func(ctx context.Context) (id string, createdAt time.Time, found bool, err error)
+
+// executeIdempotent wraps a create operation with a client-side idempotency
+// check, for the create endpoints Discord gives no native idempotency key
+// for (thread creation, message sends, webhook executes). It first runs
+// lookup; if it finds a match created within idempotencyWindow, that
+// resource's ID is adopted and create is never called. Otherwise create
+// runs and its result's ID, extracted via idOf, is returned.
+func executeIdempotent[T any](ctx context.Context, lookup idempotencyLookup, create func() (T, error), idOf func(T) string) (id string, result T, adopted bool, err error) {
+	if foundID, createdAt, found, lookupErr := lookup(ctx); lookupErr == nil && found {
+		if time.Since(createdAt) <= idempotencyWindow {
+			return foundID, result, true, nil
+		}
+	}
+
+	result, err = create()
+	if err != nil {
+		var zero T
+		return "", zero, false, err
+	}
+	return idOf(result), result, false, nil
+}
+
+// tagsMatch reports whether a and b contain the same applied tag IDs,
+// ignoring order.
+func tagsMatch(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	counts := make(map[string]int, len(a))
+	for _, tag := range a {
+		counts[tag]++
+	}
+	for _, tag := range b {
+		counts[tag]--
+	}
+	for _, n := range counts {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// hashForumPostMessage hashes a forum post's initial message content, so
+// findRecentForumPost can confirm a same-name candidate thread actually
+// matches this config's content before adopting it, without comparing raw
+// message bodies.
+func hashForumPostMessage(message string) string {
+	sum := sha256.Sum256([]byte(message))
+	return hex.EncodeToString(sum[:])
+}
+
+// forumPostIdempotencyKey deterministically derives the idempotency key for
+// a forum post create call from the fields that determine its identity.
+func forumPostIdempotencyKey(channelID, name, message string, appliedTags []string) string {
+	key := sha256.Sum256([]byte(strings.Join([]string{
+		channelID,
+		name,
+		hashForumPostMessage(message),
+		strings.Join(appliedTags, ","),
+	}, "\x00")))
+	return hex.EncodeToString(key[:])
+}
@@ -0,0 +1,125 @@
+package discord
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestForumPostIdempotencyKey_Deterministic(t *testing.T) {
+	a := forumPostIdempotencyKey("1", "name", "message", []string{"tag1", "tag2"})
+	b := forumPostIdempotencyKey("1", "name", "message", []string{"tag1", "tag2"})
+	if a != b {
+		t.Errorf("expected the same inputs to produce the same key, got %q and %q", a, b)
+	}
+}
+
+func TestForumPostIdempotencyKey_DiffersOnMessage(t *testing.T) {
+	a := forumPostIdempotencyKey("1", "name", "message one", nil)
+	b := forumPostIdempotencyKey("1", "name", "message two", nil)
+	if a == b {
+		t.Error("expected different messages to produce different keys")
+	}
+}
+
+func TestHashForumPostMessage_DeterministicAndDistinct(t *testing.T) {
+	if hashForumPostMessage("hello") != hashForumPostMessage("hello") {
+		t.Error("expected the same message to hash the same way")
+	}
+	if hashForumPostMessage("hello") == hashForumPostMessage("goodbye") {
+		t.Error("expected different messages to hash differently")
+	}
+}
+
+func TestTagsMatch_IgnoresOrder(t *testing.T) {
+	if !tagsMatch([]string{"a", "b"}, []string{"b", "a"}) {
+		t.Error("expected tagsMatch to ignore ordering")
+	}
+}
+
+func TestTagsMatch_DiffersOnContent(t *testing.T) {
+	if tagsMatch([]string{"a", "b"}, []string{"a", "c"}) {
+		t.Error("expected tagsMatch to reject a differing tag set")
+	}
+	if tagsMatch([]string{"a"}, []string{"a", "a"}) {
+		t.Error("expected tagsMatch to reject a differing tag count")
+	}
+}
+
+func TestExecuteIdempotent_AdoptsRecentMatch(t *testing.T) {
+	createCalls := 0
+
+	id, _, adopted, err := executeIdempotent(
+		context.Background(),
+		func(ctx context.Context) (string, time.Time, bool, error) {
+			return "existing-id", time.Now(), true, nil
+		},
+		func() (string, error) {
+			createCalls++
+			return "new-id", nil
+		},
+		func(s string) string { return s },
+	)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !adopted {
+		t.Error("expected a recent match to be adopted")
+	}
+	if id != "existing-id" {
+		t.Errorf("expected adopted id %q, got %q", "existing-id", id)
+	}
+	if createCalls != 0 {
+		t.Errorf("expected create to be skipped, got %d calls", createCalls)
+	}
+}
+
+func TestExecuteIdempotent_CreatesWhenNoMatch(t *testing.T) {
+	id, _, adopted, err := executeIdempotent(
+		context.Background(),
+		func(ctx context.Context) (string, time.Time, bool, error) {
+			return "", time.Time{}, false, nil
+		},
+		func() (string, error) {
+			return "new-id", nil
+		},
+		func(s string) string { return s },
+	)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if adopted {
+		t.Error("expected no match to result in a fresh create, not an adoption")
+	}
+	if id != "new-id" {
+		t.Errorf("expected created id %q, got %q", "new-id", id)
+	}
+}
+
+func TestExecuteIdempotent_IgnoresStaleMatch(t *testing.T) {
+	createCalls := 0
+
+	_, _, adopted, err := executeIdempotent(
+		context.Background(),
+		func(ctx context.Context) (string, time.Time, bool, error) {
+			return "stale-id", time.Now().Add(-2 * idempotencyWindow), true, nil
+		},
+		func() (string, error) {
+			createCalls++
+			return "new-id", nil
+		},
+		func(s string) string { return s },
+	)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if adopted {
+		t.Error("expected a stale match outside the idempotency window to not be adopted")
+	}
+	if createCalls != 1 {
+		t.Errorf("expected create to run once, got %d calls", createCalls)
+	}
+}
@@ -0,0 +1,149 @@
+package discord
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// metrics aggregates the counters and per-route latency histograms the
+// rate-limited transport and executeWithRetry record on every call. One
+// metrics instance is shared process-wide, since DISCORD_PROVIDER_METRICS_ADDR
+// exposes a single /metrics endpoint regardless of how many provider
+// configurations (Contexts) are active.
+type metrics struct {
+	totalCalls       int64
+	totalRetries     int64
+	bucketRateLimits int64
+	globalRateLimits int64
+
+	mu             sync.Mutex
+	latencyByRoute map[string]*latencyHistogram
+}
+
+// latencyHistogram is a Prometheus-style cumulative histogram: counts[i] is
+// the number of observations <= bounds[i], and the last entry is the +Inf
+// bucket.
+type latencyHistogram struct {
+	count  int64
+	sum    time.Duration
+	bounds []time.Duration
+	counts []int64
+}
+
+var defaultLatencyBounds = []time.Duration{
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	5 * time.Second,
+	30 * time.Second,
+}
+
+var defaultMetrics = &metrics{latencyByRoute: make(map[string]*latencyHistogram)}
+
+// recordCall records one completed REST call's latency against route.
+func (m *metrics) recordCall(route string, duration time.Duration) {
+	atomic.AddInt64(&m.totalCalls, 1)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	hist, ok := m.latencyByRoute[route]
+	if !ok {
+		hist = &latencyHistogram{bounds: defaultLatencyBounds, counts: make([]int64, len(defaultLatencyBounds)+1)}
+		m.latencyByRoute[route] = hist
+	}
+	hist.count++
+	hist.sum += duration
+	for i, bound := range hist.bounds {
+		if duration <= bound {
+			hist.counts[i]++
+		}
+	}
+	hist.counts[len(hist.bounds)]++ // +Inf bucket
+}
+
+// recordRateLimit records a 429 response, split by whether it was a global
+// rate limit or scoped to a single bucket.
+func (m *metrics) recordRateLimit(global bool) {
+	if global {
+		atomic.AddInt64(&m.globalRateLimits, 1)
+	} else {
+		atomic.AddInt64(&m.bucketRateLimits, 1)
+	}
+}
+
+// recordRetry records one executeWithRetry attempt that retried after a 429.
+func (m *metrics) recordRetry() {
+	atomic.AddInt64(&m.totalRetries, 1)
+}
+
+// render writes m in Prometheus text exposition format.
+func (m *metrics) render() string {
+	var b strings.Builder
+
+	fmt.Fprintln(&b, "# HELP discord_provider_api_calls_total Total Discord REST calls made.")
+	fmt.Fprintln(&b, "# TYPE discord_provider_api_calls_total counter")
+	fmt.Fprintf(&b, "discord_provider_api_calls_total %d\n", atomic.LoadInt64(&m.totalCalls))
+
+	fmt.Fprintln(&b, "# HELP discord_provider_api_retries_total Total retry attempts across all Discord REST calls.")
+	fmt.Fprintln(&b, "# TYPE discord_provider_api_retries_total counter")
+	fmt.Fprintf(&b, "discord_provider_api_retries_total %d\n", atomic.LoadInt64(&m.totalRetries))
+
+	fmt.Fprintln(&b, "# HELP discord_provider_rate_limits_total Total 429 responses, labeled by scope.")
+	fmt.Fprintln(&b, "# TYPE discord_provider_rate_limits_total counter")
+	fmt.Fprintf(&b, "discord_provider_rate_limits_total{scope=\"bucket\"} %d\n", atomic.LoadInt64(&m.bucketRateLimits))
+	fmt.Fprintf(&b, "discord_provider_rate_limits_total{scope=\"global\"} %d\n", atomic.LoadInt64(&m.globalRateLimits))
+
+	fmt.Fprintln(&b, "# HELP discord_provider_api_call_duration_seconds Discord REST call latency, labeled by route.")
+	fmt.Fprintln(&b, "# TYPE discord_provider_api_call_duration_seconds histogram")
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for route, hist := range m.latencyByRoute {
+		for i, bound := range hist.bounds {
+			fmt.Fprintf(&b, "discord_provider_api_call_duration_seconds_bucket{route=%q,le=%q} %d\n", route, formatSeconds(bound), hist.counts[i])
+		}
+		fmt.Fprintf(&b, "discord_provider_api_call_duration_seconds_bucket{route=%q,le=\"+Inf\"} %d\n", route, hist.counts[len(hist.bounds)])
+		fmt.Fprintf(&b, "discord_provider_api_call_duration_seconds_sum{route=%q} %s\n", route, formatSeconds(hist.sum))
+		fmt.Fprintf(&b, "discord_provider_api_call_duration_seconds_count{route=%q} %d\n", route, hist.count)
+	}
+
+	return b.String()
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+}
+
+// StartMetricsServer binds an HTTP listener serving Prometheus text
+// exposition at /metrics when DISCORD_PROVIDER_METRICS_ADDR is set, giving
+// operators running Terraform at scale (CI pipelines managing hundreds of
+// resources) visibility into why an apply is slow and which routes are
+// throttling. It returns nil, and binds nothing, when the env var is unset.
+func StartMetricsServer() *http.Server {
+	addr := os.Getenv("DISCORD_PROVIDER_METRICS_ADDR")
+	if addr == "" {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		_, _ = w.Write([]byte(defaultMetrics.render()))
+	})
+
+	server := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		_ = server.ListenAndServe()
+	}()
+
+	return server
+}
@@ -0,0 +1,79 @@
+package discord
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics_RecordCallAccumulatesHistogram(t *testing.T) {
+	m := &metrics{latencyByRoute: make(map[string]*latencyHistogram)}
+
+	m.recordCall("GET /channels/1/messages/{id}", 10*time.Millisecond)
+	m.recordCall("GET /channels/1/messages/{id}", 200*time.Millisecond)
+
+	hist := m.latencyByRoute["GET /channels/1/messages/{id}"]
+	if hist.count != 2 {
+		t.Fatalf("expected count 2, got %d", hist.count)
+	}
+	if hist.sum != 210*time.Millisecond {
+		t.Errorf("expected sum 210ms, got %v", hist.sum)
+	}
+
+	// 10ms falls in the 50ms bucket and every larger one; 200ms only in the
+	// 250ms bucket and larger.
+	if hist.counts[0] != 1 { // le 50ms
+		t.Errorf("expected 1 observation <=50ms, got %d", hist.counts[0])
+	}
+	if hist.counts[2] != 2 { // le 250ms
+		t.Errorf("expected 2 observations <=250ms, got %d", hist.counts[2])
+	}
+	if hist.counts[len(hist.counts)-1] != 2 { // +Inf
+		t.Errorf("expected 2 observations in the +Inf bucket, got %d", hist.counts[len(hist.counts)-1])
+	}
+}
+
+func TestMetrics_RecordRateLimitSplitsByScope(t *testing.T) {
+	m := &metrics{latencyByRoute: make(map[string]*latencyHistogram)}
+
+	m.recordRateLimit(false)
+	m.recordRateLimit(true)
+	m.recordRateLimit(true)
+
+	if m.bucketRateLimits != 1 {
+		t.Errorf("expected 1 bucket rate limit, got %d", m.bucketRateLimits)
+	}
+	if m.globalRateLimits != 2 {
+		t.Errorf("expected 2 global rate limits, got %d", m.globalRateLimits)
+	}
+}
+
+func TestMetrics_RenderIncludesPrometheusExposition(t *testing.T) {
+	m := &metrics{latencyByRoute: make(map[string]*latencyHistogram)}
+	m.recordCall("POST /channels/1/threads", 5*time.Millisecond)
+	m.recordRateLimit(true)
+	m.recordRetry()
+
+	out := m.render()
+
+	for _, want := range []string{
+		"# TYPE discord_provider_api_calls_total counter",
+		"discord_provider_api_calls_total 1",
+		"discord_provider_api_retries_total 1",
+		`discord_provider_rate_limits_total{scope="global"} 1`,
+		"# TYPE discord_provider_api_call_duration_seconds histogram",
+		`discord_provider_api_call_duration_seconds_count{route="POST /channels/1/threads"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected rendered metrics to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestStartMetricsServer_NoopWhenUnset(t *testing.T) {
+	t.Setenv("DISCORD_PROVIDER_METRICS_ADDR", "")
+
+	if server := StartMetricsServer(); server != nil {
+		t.Error("expected no server when DISCORD_PROVIDER_METRICS_ADDR is unset")
+	}
+}
@@ -0,0 +1,57 @@
+package discord
+
+import "github.com/bwmarrin/discordgo"
+
+// Context is the per-run state shared by every resource and data source in
+// a single Terraform provider configuration. It is threaded through as the
+// meta interface{} argument on all CRUD callbacks.
+type Context struct {
+	Session *discordgo.Session
+
+	// RateLimiter proactively throttles REST calls made through Session so
+	// parallel resources don't collectively blow through Discord's quotas.
+	RateLimiter *RateLimiter
+
+	// CircuitBreaker short-circuits REST calls while Discord is failing
+	// broadly, so one Terraform run doesn't hammer every resource against
+	// an outage one-by-one.
+	CircuitBreaker *CircuitBreaker
+
+	// BackoffPolicy is the default retry policy resources build their
+	// per-call BackoffPolicy from (e.g. overriding just MaxElapsedTime for a
+	// long-running create). NewContext sets it to DefaultBackoffPolicy
+	// unless a WithDefaultBackoffPolicy option overrides it; no provider
+	// schema attribute sets it.
+	BackoffPolicy BackoffPolicy
+}
+
+// ContextOption customizes a Context built by NewContext.
+type ContextOption func(*Context)
+
+// WithDefaultBackoffPolicy overrides the BackoffPolicy resources fall back
+// to.
+func WithDefaultBackoffPolicy(policy BackoffPolicy) ContextOption {
+	return func(c *Context) {
+		c.BackoffPolicy = policy
+	}
+}
+
+// NewContext builds the shared Context for session, wiring up the rate
+// limiter as session's HTTP transport so every resource benefits from it.
+func NewContext(session *discordgo.Session, opts ...ContextOption) *Context {
+	limiter := NewRateLimiter()
+	limiter.Wrap(session)
+
+	ctx := &Context{
+		Session:        session,
+		RateLimiter:    limiter,
+		CircuitBreaker: NewCircuitBreaker(),
+		BackoffPolicy:  DefaultBackoffPolicy(),
+	}
+
+	for _, opt := range opts {
+		opt(ctx)
+	}
+
+	return ctx
+}
@@ -0,0 +1,37 @@
+package discord
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestNewContext_DefaultsToDefaultBackoffPolicy(t *testing.T) {
+	session, err := discordgo.New("Bot token")
+	if err != nil {
+		t.Fatalf("failed to build session: %v", err)
+	}
+
+	ctx := NewContext(session)
+
+	if ctx.BackoffPolicy != DefaultBackoffPolicy() {
+		t.Errorf("expected BackoffPolicy to default to DefaultBackoffPolicy(), got %+v", ctx.BackoffPolicy)
+	}
+}
+
+func TestNewContext_WithDefaultBackoffPolicyOverrides(t *testing.T) {
+	session, err := discordgo.New("Bot token")
+	if err != nil {
+		t.Fatalf("failed to build session: %v", err)
+	}
+
+	policy := DefaultBackoffPolicy()
+	policy.MaxElapsedTime = 90 * time.Second
+
+	ctx := NewContext(session, WithDefaultBackoffPolicy(policy))
+
+	if ctx.BackoffPolicy.MaxElapsedTime != 90*time.Second {
+		t.Errorf("expected WithDefaultBackoffPolicy to override MaxElapsedTime, got %v", ctx.BackoffPolicy.MaxElapsedTime)
+	}
+}
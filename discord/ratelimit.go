@@ -0,0 +1,580 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	maxRetries  = 5
+	baseBackoff = 1 * time.Second
+	maxBackoff  = 120 * time.Second
+)
+
+// ErrMaxElapsedTime is returned (wrapped) by executeWithRetry when the
+// cumulative time spent waiting between retries exceeds the active
+// BackoffPolicy's MaxElapsedTime.
+var ErrMaxElapsedTime = errors.New("max elapsed retry time exceeded")
+
+// RateLimitError represents a Discord rate limit response
+type RateLimitError struct {
+	Message    string  `json:"message"`
+	RetryAfter float64 `json:"retry_after"`
+	Global     bool    `json:"global"`
+}
+
+// BackoffPolicy configures how executeWithRetry spaces out retries. The
+// zero value is not valid; build one from DefaultBackoffPolicy and
+// override only the fields that need to differ.
+type BackoffPolicy struct {
+	// MaxRetries is the maximum number of attempts, including the first.
+	MaxRetries int
+	// InitialInterval is the backoff cap used for the first retry.
+	InitialInterval time.Duration
+	// MaxInterval is the highest backoff cap any attempt can reach.
+	MaxInterval time.Duration
+	// Multiplier grows the backoff cap between attempts (InitialInterval * Multiplier^attempt).
+	Multiplier float64
+	// RandomizationFactor controls how much of the backoff cap is
+	// randomized away. At 1 (the default) this is AWS's "full jitter":
+	// the actual wait is uniformly random between 0 and the cap. At 0,
+	// backoff is deterministic.
+	RandomizationFactor float64
+	// MaxElapsedTime bounds the cumulative time spent waiting across all
+	// retries for one call. Zero means no ceiling.
+	MaxElapsedTime time.Duration
+}
+
+// DefaultBackoffPolicy mirrors the provider's historical fixed behavior:
+// up to 5 attempts, doubling from 1s up to a 120s cap, fully jittered.
+func DefaultBackoffPolicy() BackoffPolicy {
+	return BackoffPolicy{
+		MaxRetries:          maxRetries,
+		InitialInterval:     baseBackoff,
+		MaxInterval:         maxBackoff,
+		Multiplier:          2,
+		RandomizationFactor: 1,
+	}
+}
+
+// nextInterval returns the jittered wait before retrying attempt, following
+// the AWS "full jitter" algorithm: sleep = random_between(0, min(cap, base*2^attempt)).
+func (p BackoffPolicy) nextInterval(attempt int) time.Duration {
+	backoffCap := time.Duration(float64(p.InitialInterval) * math.Pow(p.Multiplier, float64(attempt)))
+	if backoffCap <= 0 || backoffCap > p.MaxInterval {
+		backoffCap = p.MaxInterval
+	}
+
+	if p.RandomizationFactor <= 0 {
+		return backoffCap
+	}
+
+	jitterCap := time.Duration(float64(backoffCap) * p.RandomizationFactor)
+	if jitterCap <= 0 {
+		return backoffCap
+	}
+	if jitterCap > backoffCap {
+		jitterCap = backoffCap
+	}
+	return backoffCap - time.Duration(rand.Int63n(int64(jitterCap)+1))
+}
+
+// retryConfig holds the per-call settings executeWithRetry assembles from
+// its RetryOptions.
+type retryConfig struct {
+	policy BackoffPolicy
+}
+
+// RetryOption customizes a single executeWithRetry call.
+type RetryOption func(*retryConfig)
+
+// WithBackoffPolicy overrides the default BackoffPolicy for one call, e.g.
+// for long-running operations that should tolerate a longer MaxElapsedTime.
+func WithBackoffPolicy(policy BackoffPolicy) RetryOption {
+	return func(c *retryConfig) {
+		c.policy = policy
+	}
+}
+
+// executeWithRetry wraps discordgo API calls with rate limit handling
+func executeWithRetry[T any](ctx context.Context, operation func() (T, error), opts ...RetryOption) (T, error) {
+	cfg := retryConfig{policy: DefaultBackoffPolicy()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	policy := cfg.policy
+
+	var result T
+	var err error
+	var waited time.Duration
+
+	for attempt := 0; attempt < policy.MaxRetries; attempt++ {
+		// Check if context is cancelled
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		result, err = operation()
+
+		if err == nil {
+			return result, nil
+		}
+
+		// Check if it's a rate limit error
+		var restErr *discordgo.RESTError
+		if errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == 429 {
+			retryAfter := parseRetryAfter(restErr)
+			waitDuration := calculateBackoff(policy, attempt, retryAfter)
+
+			if policy.MaxElapsedTime > 0 && waited+waitDuration > policy.MaxElapsedTime {
+				return result, fmt.Errorf("retry elapsed time would exceed %s: %w", policy.MaxElapsedTime, ErrMaxElapsedTime)
+			}
+			waited += waitDuration
+
+			defaultMetrics.recordRetry()
+			tflog.Debug(ctx, "discord api call rate limited, retrying", map[string]interface{}{
+				"attempt": attempt,
+				"wait_ms": waitDuration.Milliseconds(),
+			})
+
+			// Wait with context cancellation support
+			select {
+			case <-ctx.Done():
+				return result, ctx.Err()
+			case <-time.After(waitDuration):
+				continue
+			}
+		}
+
+		// Not a rate limit error, return immediately
+		return result, classify(err)
+	}
+
+	return result, &retriesExhaustedError{attempts: policy.MaxRetries, err: classify(err)}
+}
+
+// executeWithRetryNoResult wraps discordgo API calls that return only an error
+func executeWithRetryNoResult(ctx context.Context, operation func() error, opts ...RetryOption) error {
+	_, err := executeWithRetry(ctx, func() (struct{}, error) {
+		return struct{}{}, operation()
+	}, opts...)
+	return err
+}
+
+// parseRetryAfter extracts the retry duration from a 429 response
+func parseRetryAfter(restErr *discordgo.RESTError) time.Duration {
+	if restErr.Response == nil {
+		return 5 * time.Second
+	}
+
+	// Try Retry-After header first
+	if retryAfterStr := restErr.Response.Header.Get("Retry-After"); retryAfterStr != "" {
+		if seconds, err := strconv.ParseFloat(retryAfterStr, 64); err == nil {
+			return time.Duration(seconds * float64(time.Second))
+		}
+	}
+
+	// Try parsing JSON body from the response body in the error
+	if restErr.ResponseBody != nil {
+		var rateLimitErr RateLimitError
+		if json.Unmarshal(restErr.ResponseBody, &rateLimitErr) == nil && rateLimitErr.RetryAfter > 0 {
+			return time.Duration(rateLimitErr.RetryAfter * float64(time.Second))
+		}
+	}
+
+	// Try reading from Response.Body if ResponseBody is nil
+	if restErr.Response.Body != nil {
+		body, err := io.ReadAll(restErr.Response.Body)
+		if err == nil {
+			var rateLimitErr RateLimitError
+			if json.Unmarshal(body, &rateLimitErr) == nil && rateLimitErr.RetryAfter > 0 {
+				return time.Duration(rateLimitErr.RetryAfter * float64(time.Second))
+			}
+		}
+	}
+
+	// Default fallback
+	return 5 * time.Second
+}
+
+// calculateBackoff returns the wait duration before the next attempt,
+// preferring Discord's Retry-After hint when present and reasonable, and
+// otherwise following policy's full-jitter exponential backoff.
+func calculateBackoff(policy BackoffPolicy, attempt int, retryAfter time.Duration) time.Duration {
+	// Use Retry-After if provided and reasonable
+	if retryAfter > 0 && retryAfter < policy.MaxInterval {
+		// Add small buffer
+		return retryAfter + 500*time.Millisecond
+	}
+
+	return policy.nextInterval(attempt)
+}
+
+// RateLimiter proactively throttles outgoing Discord REST calls using the
+// X-RateLimit-* headers Discord returns on every response, so that
+// executeWithRetry's 429 handling becomes a safety net rather than the
+// primary defense against rate limits. One RateLimiter is shared across all
+// resources for the lifetime of a Context, since Discord scopes most quotas
+// per bucket (route + major parameter) and some globally per bot token.
+type RateLimiter struct {
+	buckets      sync.Map // bucket key (string) -> *bucketState
+	routeBuckets sync.Map // route template (string) -> discovered Discord bucket hash (string)
+
+	globalMu      sync.Mutex
+	globalResetAt time.Time
+}
+
+// bucketState tracks the remaining quota for a single Discord rate limit
+// bucket, as reported by X-RateLimit-Remaining/X-RateLimit-Reset-After.
+type bucketState struct {
+	mu        sync.Mutex
+	remaining int
+	resetAt   time.Time
+}
+
+// NewRateLimiter returns a RateLimiter with no buckets populated; buckets
+// are learned lazily from response headers as calls are made.
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{}
+}
+
+// Wrap installs the rate limiter as session's HTTP transport, so every REST
+// call discordgo makes is throttled and observed.
+func (rl *RateLimiter) Wrap(session *discordgo.Session) {
+	base := session.Client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	session.Client.Transport = &rateLimitTransport{limiter: rl, base: base}
+}
+
+// rateLimitTransport is an http.RoundTripper that blocks requests which
+// would exceed a bucket's known remaining quota before sending them, and
+// records the quota Discord reports on the way back.
+type rateLimitTransport struct {
+	limiter *RateLimiter
+	base    http.RoundTripper
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	route := routeKey(req)
+
+	t.limiter.waitForGlobal(req.Context())
+	t.limiter.waitForBucket(req.Context(), route)
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	duration := time.Since(start)
+
+	if err != nil {
+		tflog.Debug(req.Context(), "discord api call failed", map[string]interface{}{
+			"route":       route,
+			"duration_ms": duration.Milliseconds(),
+			"error":       err.Error(),
+		})
+		return resp, err
+	}
+
+	t.limiter.observe(route, resp)
+	defaultMetrics.recordCall(route, duration)
+
+	tflog.Debug(req.Context(), "discord api call", map[string]interface{}{
+		"route":       route,
+		"status":      resp.StatusCode,
+		"duration_ms": duration.Milliseconds(),
+		"bucket":      resp.Header.Get("X-RateLimit-Bucket"),
+		"retry_after": resp.Header.Get("Retry-After"),
+	})
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		defaultMetrics.recordRateLimit(resp.Header.Get("X-RateLimit-Global") == "true")
+	}
+
+	return resp, nil
+}
+
+// majorParam matches the leading /channels/{id}, /guilds/{id}, or
+// /webhooks/{id} segment of a route, which is what Discord scopes most
+// bucket quotas by.
+var majorParam = regexp.MustCompile(`^/(channels|guilds|webhooks)/\d{15,20}`)
+
+// apiPrefix matches the /api/vN prefix discordgo puts in front of every REST
+// path; routeKey strips it so majorParam can match at the start of the route.
+var apiPrefix = regexp.MustCompile(`^/api/v\d+`)
+
+// minorID matches any other numeric ID segment (message IDs, etc.) so
+// routes that only differ by those collapse into the same template.
+var minorID = regexp.MustCompile(`/\d{15,20}`)
+
+// routeKey reduces a request to a stable bucket lookup key: its method plus
+// a route template with the major parameter preserved and all other IDs
+// collapsed, e.g. "POST /channels/123456789012345678/{id}".
+func routeKey(req *http.Request) string {
+	path := apiPrefix.ReplaceAllString(req.URL.Path, "")
+	major := majorParam.FindString(path)
+	rest := minorID.ReplaceAllString(path[len(major):], "/{id}")
+	return req.Method + " " + major + rest
+}
+
+// waitForGlobal blocks until any outstanding global rate limit (the kind
+// Discord applies across all buckets for a bot token) has elapsed.
+func (rl *RateLimiter) waitForGlobal(ctx context.Context) {
+	rl.globalMu.Lock()
+	resetAt := rl.globalResetAt
+	rl.globalMu.Unlock()
+
+	waitUntil(ctx, resetAt)
+}
+
+// waitForBucket blocks until the bucket backing route has quota remaining.
+func (rl *RateLimiter) waitForBucket(ctx context.Context, route string) {
+	state := rl.bucketFor(rl.resolvedKey(route))
+
+	state.mu.Lock()
+	resetAt := state.resetAt
+	exhausted := state.remaining <= 0
+	state.mu.Unlock()
+
+	if exhausted {
+		waitUntil(ctx, resetAt)
+	}
+}
+
+// observe records the rate limit quota a response reported, keyed by
+// Discord's own bucket hash once known, or the route template until then.
+func (rl *RateLimiter) observe(route string, resp *http.Response) {
+	header := resp.Header
+
+	if resp.StatusCode == http.StatusTooManyRequests && header.Get("X-RateLimit-Global") == "true" {
+		if retryAfter, err := strconv.ParseFloat(header.Get("Retry-After"), 64); err == nil {
+			rl.globalMu.Lock()
+			rl.globalResetAt = time.Now().Add(time.Duration(retryAfter * float64(time.Second)))
+			rl.globalMu.Unlock()
+		}
+		return
+	}
+
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	resetAfter, err := strconv.ParseFloat(header.Get("X-RateLimit-Reset-After"), 64)
+	if err != nil {
+		return
+	}
+
+	key := route
+	if hash := header.Get("X-RateLimit-Bucket"); hash != "" {
+		key = hash
+		rl.routeBuckets.Store(route, hash)
+	}
+
+	state := rl.bucketFor(key)
+	state.mu.Lock()
+	state.remaining = remaining
+	state.resetAt = time.Now().Add(time.Duration(resetAfter * float64(time.Second)))
+	state.mu.Unlock()
+}
+
+// resolvedKey returns the Discord bucket hash learned for route, or route
+// itself if no response for it has been observed yet.
+func (rl *RateLimiter) resolvedKey(route string) string {
+	if hash, ok := rl.routeBuckets.Load(route); ok {
+		return hash.(string)
+	}
+	return route
+}
+
+func (rl *RateLimiter) bucketFor(key string) *bucketState {
+	v, _ := rl.buckets.LoadOrStore(key, &bucketState{})
+	return v.(*bucketState)
+}
+
+// waitUntil blocks until resetAt or ctx is cancelled, whichever comes first.
+// A zero or past resetAt returns immediately.
+func waitUntil(ctx context.Context, resetAt time.Time) {
+	wait := time.Until(resetAt)
+	if wait <= 0 {
+		return
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(wait):
+	}
+}
+
+// ErrCircuitOpen is returned when a CircuitBreaker is open and
+// short-circuiting calls because Discord appears to be broadly failing.
+var ErrCircuitOpen = errors.New("circuit breaker open: Discord API is failing broadly")
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+const (
+	defaultCircuitFailureThreshold = 5
+	defaultCircuitCooldown         = 30 * time.Second
+	maxCircuitCooldown             = 5 * time.Minute
+)
+
+// CircuitBreaker guards executeWithRetry against hammering Discord during a
+// broad outage. It trips open after a run of consecutive 5xx or
+// global-429 failures, short-circuits subsequent calls with ErrCircuitOpen
+// until a cooldown elapses, then allows a single half-open probe: success
+// closes the circuit again, failure reopens it with a doubled cooldown. One
+// CircuitBreaker is shared across all resources for the lifetime of a
+// Context, since an outage affects every resource in a Terraform run alike.
+type CircuitBreaker struct {
+	mu               sync.Mutex
+	state            circuitState
+	consecutiveFails int
+	failureThreshold int
+	cooldown         time.Duration
+	openedAt         time.Time
+}
+
+// NewCircuitBreaker returns a closed CircuitBreaker with the provider's
+// default failure threshold and cooldown.
+func NewCircuitBreaker() *CircuitBreaker {
+	return &CircuitBreaker{
+		failureThreshold: defaultCircuitFailureThreshold,
+		cooldown:         defaultCircuitCooldown,
+	}
+}
+
+// allow reports whether a call may proceed. Transitioning from open to
+// half-open happens here, on the first call after the cooldown elapses.
+func (cb *CircuitBreaker) allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case circuitOpen:
+		if time.Since(cb.openedAt) < cb.cooldown {
+			return false
+		}
+		cb.state = circuitHalfOpen
+		return true
+	case circuitHalfOpen:
+		// Only one probe is allowed in flight at a time.
+		return false
+	default:
+		return true
+	}
+}
+
+// recordSuccess closes the circuit and resets its failure count and cooldown.
+func (cb *CircuitBreaker) recordSuccess() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.state = circuitClosed
+	cb.consecutiveFails = 0
+	cb.cooldown = defaultCircuitCooldown
+}
+
+// recordFailure registers a failed call. tripping is false for errors (like
+// a 404) that shouldn't count towards opening the circuit.
+func (cb *CircuitBreaker) recordFailure(tripping bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if !tripping {
+		// Discord answered, so any run of consecutive tripping failures ends.
+		cb.consecutiveFails = 0
+		// A non-tripping error (like a 404) still means Discord answered the
+		// probe, so treat it as evidence the outage is over rather than
+		// leaving the circuit stuck half-open.
+		if cb.state == circuitHalfOpen {
+			cb.state = circuitClosed
+			cb.consecutiveFails = 0
+			cb.cooldown = defaultCircuitCooldown
+		}
+		return
+	}
+
+	if cb.state == circuitHalfOpen {
+		// The probe failed with a tripping error: reopen and back off
+		// further next time.
+		cb.state = circuitOpen
+		cb.openedAt = time.Now()
+		cb.cooldown *= 2
+		if cb.cooldown > maxCircuitCooldown {
+			cb.cooldown = maxCircuitCooldown
+		}
+		return
+	}
+
+	cb.consecutiveFails++
+	if cb.consecutiveFails >= cb.failureThreshold {
+		cb.state = circuitOpen
+		cb.openedAt = time.Now()
+	}
+}
+
+// executeWithCircuitBreaker runs executeWithRetry behind cb, short-circuiting
+// with ErrCircuitOpen while the breaker is open. A nil cb disables the
+// breaker entirely and behaves exactly like executeWithRetry.
+func executeWithCircuitBreaker[T any](ctx context.Context, cb *CircuitBreaker, operation func() (T, error), opts ...RetryOption) (T, error) {
+	if cb == nil {
+		return executeWithRetry(ctx, operation, opts...)
+	}
+
+	var zero T
+	if !cb.allow() {
+		return zero, ErrCircuitOpen
+	}
+
+	result, err := executeWithRetry(ctx, operation, opts...)
+	if err != nil {
+		cb.recordFailure(isCircuitTrippingError(err))
+		return result, err
+	}
+
+	cb.recordSuccess()
+	return result, nil
+}
+
+// executeWithCircuitBreakerNoResult is executeWithCircuitBreaker for calls
+// that return only an error.
+func executeWithCircuitBreakerNoResult(ctx context.Context, cb *CircuitBreaker, operation func() error, opts ...RetryOption) error {
+	_, err := executeWithCircuitBreaker(ctx, cb, func() (struct{}, error) {
+		return struct{}{}, operation()
+	}, opts...)
+	return err
+}
+
+// isCircuitTrippingError reports whether err represents the kind of broad
+// failure (a 5xx, a global 429, or exhausting MaxElapsedTime) the circuit
+// breaker should count towards tripping open, as opposed to an ordinary
+// per-resource error like a 404.
+func isCircuitTrippingError(err error) bool {
+	if errors.Is(err, ErrGlobalRateLimit) || errors.Is(err, ErrMaxElapsedTime) {
+		return true
+	}
+
+	var de *DiscordError
+	if errors.As(err, &de) {
+		return de.HTTPStatus >= 500
+	}
+
+	return false
+}
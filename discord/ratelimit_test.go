@@ -0,0 +1,549 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestParseRetryAfter_Header(t *testing.T) {
+	restErr := &discordgo.RESTError{
+		Response: &http.Response{
+			StatusCode: 429,
+			Header:     http.Header{"Retry-After": []string{"5"}},
+		},
+	}
+
+	duration := parseRetryAfter(restErr)
+	expected := 5 * time.Second
+	if duration != expected {
+		t.Errorf("expected %v, got %v", expected, duration)
+	}
+}
+
+func TestParseRetryAfter_HeaderFloat(t *testing.T) {
+	restErr := &discordgo.RESTError{
+		Response: &http.Response{
+			StatusCode: 429,
+			Header:     http.Header{"Retry-After": []string{"1.5"}},
+		},
+	}
+
+	duration := parseRetryAfter(restErr)
+	expected := 1500 * time.Millisecond
+	if duration != expected {
+		t.Errorf("expected %v, got %v", expected, duration)
+	}
+}
+
+func TestParseRetryAfter_JSONBody(t *testing.T) {
+	restErr := &discordgo.RESTError{
+		Response: &http.Response{
+			StatusCode: 429,
+			Header:     http.Header{},
+		},
+		ResponseBody: []byte(`{"message": "You are being rate limited.", "retry_after": 3.0, "global": false}`),
+	}
+
+	duration := parseRetryAfter(restErr)
+	expected := 3 * time.Second
+	if duration != expected {
+		t.Errorf("expected %v, got %v", expected, duration)
+	}
+}
+
+func TestParseRetryAfter_Fallback(t *testing.T) {
+	restErr := &discordgo.RESTError{
+		Response: &http.Response{
+			StatusCode: 429,
+			Header:     http.Header{},
+		},
+	}
+
+	duration := parseRetryAfter(restErr)
+	expected := 5 * time.Second
+	if duration != expected {
+		t.Errorf("expected %v, got %v", expected, duration)
+	}
+}
+
+func TestParseRetryAfter_NilResponse(t *testing.T) {
+	restErr := &discordgo.RESTError{
+		Response: nil,
+	}
+
+	duration := parseRetryAfter(restErr)
+	expected := 5 * time.Second
+	if duration != expected {
+		t.Errorf("expected %v, got %v", expected, duration)
+	}
+}
+
+func TestCalculateBackoff_WithRetryAfter(t *testing.T) {
+	duration := calculateBackoff(DefaultBackoffPolicy(), 0, 3*time.Second)
+	expected := 3*time.Second + 500*time.Millisecond
+	if duration != expected {
+		t.Errorf("expected %v, got %v", expected, duration)
+	}
+}
+
+func TestCalculateBackoff_ExponentialBackoffCaps(t *testing.T) {
+	// RandomizationFactor 0 makes the cap deterministic so exact caps can
+	// be asserted; full jitter's randomized wait is covered separately.
+	policy := DefaultBackoffPolicy()
+	policy.RandomizationFactor = 0
+
+	tests := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{0, 1 * time.Second},
+		{1, 2 * time.Second},
+		{2, 4 * time.Second},
+		{3, 8 * time.Second},
+		{4, 16 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("attempt_%d", tt.attempt), func(t *testing.T) {
+			duration := calculateBackoff(policy, tt.attempt, 0)
+			if duration != tt.expected {
+				t.Errorf("attempt %d: expected %v, got %v", tt.attempt, tt.expected, duration)
+			}
+		})
+	}
+}
+
+func TestCalculateBackoff_CapsAtMax(t *testing.T) {
+	policy := DefaultBackoffPolicy()
+	policy.RandomizationFactor = 0
+
+	duration := calculateBackoff(policy, 10, 0)
+	if duration != maxBackoff {
+		t.Errorf("expected max backoff %v, got %v", maxBackoff, duration)
+	}
+}
+
+func TestCalculateBackoff_IgnoresExcessiveRetryAfter(t *testing.T) {
+	// If Retry-After exceeds maxBackoff, fall through to exponential backoff
+	policy := DefaultBackoffPolicy()
+	policy.RandomizationFactor = 0
+
+	duration := calculateBackoff(policy, 0, 200*time.Second)
+	expected := 1 * time.Second // attempt 0 exponential backoff
+	if duration != expected {
+		t.Errorf("expected %v, got %v", expected, duration)
+	}
+}
+
+func TestCalculateBackoff_FullJitterStaysWithinBounds(t *testing.T) {
+	policy := DefaultBackoffPolicy()
+
+	for attempt := 0; attempt < 5; attempt++ {
+		cap := baseBackoff * time.Duration(1<<uint(attempt))
+		if cap > maxBackoff {
+			cap = maxBackoff
+		}
+
+		for i := 0; i < 20; i++ {
+			duration := calculateBackoff(policy, attempt, 0)
+			if duration < 0 || duration > cap {
+				t.Errorf("attempt %d: duration %v outside [0, %v]", attempt, duration, cap)
+			}
+		}
+	}
+}
+
+func TestExecuteWithRetry_Success(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+
+	result, err := executeWithRetry(ctx, func() (string, error) {
+		calls++
+		return "ok", nil
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != "ok" {
+		t.Errorf("expected 'ok', got '%s'", result)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestExecuteWithRetry_NonRateLimitError(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+
+	_, err := executeWithRetry(ctx, func() (string, error) {
+		calls++
+		return "", fmt.Errorf("some other error")
+	})
+
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call (no retry for non-rate-limit errors), got %d", calls)
+	}
+}
+
+func TestExecuteWithRetry_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := executeWithRetry(ctx, func() (string, error) {
+		return "ok", nil
+	})
+
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestExecuteWithRetry_MaxElapsedTimeExceeded(t *testing.T) {
+	ctx := context.Background()
+
+	policy := DefaultBackoffPolicy()
+	policy.MaxRetries = 10
+	policy.InitialInterval = time.Second
+	policy.MaxElapsedTime = 100 * time.Millisecond
+
+	rateLimited := &discordgo.RESTError{
+		Response: &http.Response{
+			StatusCode: 429,
+			Header:     http.Header{},
+		},
+	}
+
+	_, err := executeWithRetry(ctx, func() (string, error) {
+		return "", rateLimited
+	}, WithBackoffPolicy(policy))
+
+	if !errors.Is(err, ErrMaxElapsedTime) {
+		t.Errorf("expected ErrMaxElapsedTime, got %v", err)
+	}
+}
+
+func TestExecuteWithRetryNoResult_Success(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+
+	err := executeWithRetryNoResult(ctx, func() error {
+		calls++
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestExecuteWithRetryNoResult_Error(t *testing.T) {
+	ctx := context.Background()
+
+	err := executeWithRetryNoResult(ctx, func() error {
+		return fmt.Errorf("failed")
+	})
+
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestRouteKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		expected string
+	}{
+		{
+			name:     "channel route keeps major param",
+			method:   "POST",
+			path:     "/channels/123456789012345678/threads",
+			expected: "POST /channels/123456789012345678/threads",
+		},
+		{
+			name:     "trailing message id collapses to template",
+			method:   "DELETE",
+			path:     "/channels/123456789012345678/messages/987654321098765432",
+			expected: "DELETE /channels/123456789012345678/messages/{id}",
+		},
+		{
+			name:     "guild route keeps major param",
+			method:   "GET",
+			path:     "/guilds/111111111111111111/roles",
+			expected: "GET /guilds/111111111111111111/roles",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, "https://discord.com/api/v10"+tt.path, nil)
+			if err != nil {
+				t.Fatalf("failed to build request: %v", err)
+			}
+
+			key := routeKey(req)
+			if key != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, key)
+			}
+		})
+	}
+}
+
+func TestRateLimiter_WaitForBucketBlocksUntilReset(t *testing.T) {
+	rl := NewRateLimiter()
+	route := "GET /channels/123456789012345678/messages/{id}"
+
+	state := rl.bucketFor(route)
+	state.remaining = 0
+	state.resetAt = time.Now().Add(50 * time.Millisecond)
+
+	start := time.Now()
+	rl.waitForBucket(context.Background(), route)
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("expected to block until reset, only waited %v", elapsed)
+	}
+}
+
+func TestRateLimiter_WaitForBucketSkipsWhenQuotaRemains(t *testing.T) {
+	rl := NewRateLimiter()
+	route := "GET /channels/123456789012345678/messages/{id}"
+
+	state := rl.bucketFor(route)
+	state.remaining = 3
+	state.resetAt = time.Now().Add(time.Hour)
+
+	start := time.Now()
+	rl.waitForBucket(context.Background(), route)
+	if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+		t.Errorf("expected no wait with quota remaining, waited %v", elapsed)
+	}
+}
+
+func TestRateLimiter_ObserveTracksBucketHash(t *testing.T) {
+	rl := NewRateLimiter()
+	route := "GET /channels/123456789012345678/messages/{id}"
+
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"X-RateLimit-Bucket":      []string{"abcd1234"},
+			"X-RateLimit-Remaining":   []string{"0"},
+			"X-RateLimit-Reset-After": []string{"1.5"},
+		},
+	}
+
+	rl.observe(route, resp)
+
+	state := rl.bucketFor("abcd1234")
+	state.mu.Lock()
+	remaining := state.remaining
+	state.mu.Unlock()
+
+	if remaining != 0 {
+		t.Errorf("expected remaining 0, got %d", remaining)
+	}
+
+	resolved := rl.resolvedKey(route)
+	if resolved != "abcd1234" {
+		t.Errorf("expected route to resolve to discovered bucket hash, got %q", resolved)
+	}
+}
+
+func TestRateLimiter_ObserveHandlesGlobalRateLimit(t *testing.T) {
+	rl := NewRateLimiter()
+	route := "POST /channels/123456789012345678/threads"
+
+	resp := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header: http.Header{
+			"X-RateLimit-Global": []string{"true"},
+			"Retry-After":        []string{"0.05"},
+		},
+	}
+
+	rl.observe(route, resp)
+
+	start := time.Now()
+	rl.waitForGlobal(context.Background())
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("expected to block for global reset, only waited %v", elapsed)
+	}
+}
+
+func serverError() error {
+	return &discordgo.RESTError{
+		Response: &http.Response{
+			StatusCode: 503,
+			Header:     http.Header{},
+		},
+	}
+}
+
+func TestCircuitBreaker_TripsOpenAfterConsecutiveFailures(t *testing.T) {
+	cb := NewCircuitBreaker()
+	cb.failureThreshold = 3
+
+	for i := 0; i < 3; i++ {
+		if !cb.allow() {
+			t.Fatalf("expected call %d to be allowed before tripping", i)
+		}
+		cb.recordFailure(true)
+	}
+
+	if cb.allow() {
+		t.Error("expected circuit to be open and reject the call")
+	}
+}
+
+func TestCircuitBreaker_NonTrippingFailuresDontTrip(t *testing.T) {
+	cb := NewCircuitBreaker()
+	cb.failureThreshold = 2
+
+	for i := 0; i < 5; i++ {
+		cb.recordFailure(false)
+	}
+
+	if !cb.allow() {
+		t.Error("expected circuit to stay closed for non-tripping failures")
+	}
+}
+
+func TestCircuitBreaker_HalfOpenAfterCooldownThenCloses(t *testing.T) {
+	cb := NewCircuitBreaker()
+	cb.failureThreshold = 1
+	cb.cooldown = 10 * time.Millisecond
+
+	cb.allow()
+	cb.recordFailure(true)
+	if cb.allow() {
+		t.Fatal("expected circuit to be open immediately after tripping")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if !cb.allow() {
+		t.Fatal("expected a half-open probe to be allowed after cooldown")
+	}
+	cb.recordSuccess()
+
+	if !cb.allow() {
+		t.Error("expected circuit to be closed after a successful probe")
+	}
+}
+
+func TestCircuitBreaker_HalfOpenProbeFailureReopensWithDoubledCooldown(t *testing.T) {
+	cb := NewCircuitBreaker()
+	cb.failureThreshold = 1
+	cb.cooldown = 10 * time.Millisecond
+
+	cb.allow()
+	cb.recordFailure(true)
+	time.Sleep(20 * time.Millisecond)
+
+	cb.allow() // half-open probe
+	cb.recordFailure(true)
+
+	if cb.cooldown != 20*time.Millisecond {
+		t.Errorf("expected cooldown to double to 20ms, got %v", cb.cooldown)
+	}
+	if cb.allow() {
+		t.Error("expected circuit to reopen after a failed probe")
+	}
+}
+
+func TestCircuitBreaker_HalfOpenNonTrippingFailureCloses(t *testing.T) {
+	cb := NewCircuitBreaker()
+	cb.failureThreshold = 1
+	cb.cooldown = 10 * time.Millisecond
+
+	cb.allow()
+	cb.recordFailure(true)
+	time.Sleep(20 * time.Millisecond)
+
+	cb.allow() // half-open probe
+	cb.recordFailure(false)
+
+	if cb.state != circuitClosed {
+		t.Errorf("expected a non-tripping probe failure to close the circuit, got state %v", cb.state)
+	}
+	if cb.cooldown != defaultCircuitCooldown {
+		t.Errorf("expected cooldown to reset to the default, got %v", cb.cooldown)
+	}
+	if !cb.allow() {
+		t.Error("expected the circuit to allow calls once closed")
+	}
+}
+
+func TestExecuteWithCircuitBreaker_OpenShortCircuits(t *testing.T) {
+	cb := NewCircuitBreaker()
+	cb.failureThreshold = 1
+	cb.cooldown = time.Hour
+
+	calls := 0
+	_, err := executeWithCircuitBreaker(context.Background(), cb, func() (string, error) {
+		calls++
+		return "", serverError()
+	})
+	if err == nil {
+		t.Fatal("expected the first call's server error to propagate")
+	}
+
+	_, err = executeWithCircuitBreaker(context.Background(), cb, func() (string, error) {
+		calls++
+		return "ok", nil
+	})
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected the short-circuited call to never run the operation, got %d calls", calls)
+	}
+}
+
+func TestIsCircuitTrippingError(t *testing.T) {
+	// isCircuitTrippingError is only ever called on errors executeWithRetry
+	// has already run through classify, so exercise it the same way here.
+	notFound := &discordgo.RESTError{Response: &http.Response{StatusCode: 404, Header: http.Header{}}}
+	if isCircuitTrippingError(classify(notFound)) {
+		t.Error("expected 404 to not trip the circuit")
+	}
+
+	if !isCircuitTrippingError(classify(serverError())) {
+		t.Error("expected a 5xx to trip the circuit")
+	}
+
+	globalLimit := &discordgo.RESTError{
+		Response: &http.Response{
+			StatusCode: 429,
+			Header:     http.Header{"X-RateLimit-Global": []string{"true"}},
+		},
+	}
+	if !isCircuitTrippingError(classify(globalLimit)) {
+		t.Error("expected a global 429 to trip the circuit")
+	}
+
+	bucketLimit := &discordgo.RESTError{
+		Response: &http.Response{
+			StatusCode: 429,
+			Header:     http.Header{},
+		},
+	}
+	if isCircuitTrippingError(classify(bucketLimit)) {
+		t.Error("expected a per-bucket 429 to not trip the circuit")
+	}
+}
@@ -2,6 +2,8 @@ package discord
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -68,12 +70,18 @@ func resourceDiscordForumPost() *schema.Resource {
 				Computed:    true,
 				Description: "The ID of the user who created the post.",
 			},
+			"idempotency_key": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Internal key derived from channel_id, name, message and applied_tags when the post was created. It is recorded for diagnostics only; recovery after a crashed apply matches recent threads by those fields, not by this key.",
+			},
 		},
 	}
 }
 
 func resourceForumPostCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*Context).Session
+	providerCtx := m.(*Context)
+	client := providerCtx.Session
 
 	channelId := d.Get("channel_id").(string)
 	name := d.Get("name").(string)
@@ -88,30 +96,50 @@ func resourceForumPostCreate(ctx context.Context, d *schema.ResourceData, m inte
 		}
 	}
 
-	// Create forum post with retry handling
-	thread, err := executeWithRetry(ctx, func() (*discordgo.Channel, error) {
-		return client.ForumThreadStartComplex(channelId, &discordgo.ThreadStart{
-			Name:                name,
-			AutoArchiveDuration: autoArchive,
-			AppliedTags:         appliedTags,
-		}, &discordgo.MessageSend{
-			Content: message,
-		}, discordgo.WithContext(ctx))
-	})
+	// Thread creation can take a while to clear Discord's queue under heavy
+	// rate limiting, so bound its cumulative retry wait at two minutes,
+	// replacing whatever MaxElapsedTime the provider's default policy sets
+	// (none, by default).
+	policy := providerCtx.BackoffPolicy
+	policy.MaxElapsedTime = 2 * time.Minute
+
+	key := forumPostIdempotencyKey(channelId, name, message, appliedTags)
+	messageHash := hashForumPostMessage(message)
+
+	threadID, _, _, err := executeIdempotent(
+		ctx,
+		func(ctx context.Context) (string, time.Time, bool, error) {
+			return findRecentForumPost(ctx, providerCtx, channelId, name, messageHash, appliedTags)
+		},
+		func() (*discordgo.Channel, error) {
+			return executeWithCircuitBreaker(ctx, providerCtx.CircuitBreaker, func() (*discordgo.Channel, error) {
+				return client.ForumThreadStartComplex(channelId, &discordgo.ThreadStart{
+					Name:                name,
+					AutoArchiveDuration: autoArchive,
+					AppliedTags:         appliedTags,
+				}, &discordgo.MessageSend{
+					Content: message,
+				}, discordgo.WithContext(ctx))
+			}, WithBackoffPolicy(policy))
+		},
+		func(thread *discordgo.Channel) string { return thread.ID },
+	)
 
 	if err != nil {
 		return diag.Errorf("Failed to create forum post: %s", err.Error())
 	}
 
-	d.SetId(thread.ID)
-	d.Set("thread_id", thread.ID)
-	d.Set("owner_id", thread.OwnerID)
+	d.SetId(threadID)
+	d.Set("thread_id", threadID)
+	d.Set(idempotencyKeyAttr, key)
 
-	// Handle pinning if requested
+	// Handle pinning if requested. This is safe to re-run even when the
+	// thread was adopted from a prior crashed attempt: pinning is itself
+	// idempotent on Discord's side.
 	if d.Get("pinned").(bool) {
 		flags := discordgo.ChannelFlagPinned
-		err := executeWithRetryNoResult(ctx, func() error {
-			_, err := client.ChannelEditComplex(thread.ID, &discordgo.ChannelEdit{
+		err := executeWithCircuitBreakerNoResult(ctx, providerCtx.CircuitBreaker, func() error {
+			_, err := client.ChannelEditComplex(threadID, &discordgo.ChannelEdit{
 				Flags: &flags,
 			}, discordgo.WithContext(ctx))
 			return err
@@ -124,21 +152,70 @@ func resourceForumPostCreate(ctx context.Context, d *schema.ResourceData, m inte
 	return resourceForumPostRead(ctx, d, m)
 }
 
+// findRecentForumPost looks for an active thread in channelID named name
+// that was created within idempotencyWindow, to recover from a Terraform
+// apply that crashed after creating the thread but before saving its ID to
+// state. A channel/name match alone isn't enough to adopt a thread - two
+// unrelated configs (or a manually-created thread) can share both within the
+// window - so candidates are only adopted once their starter message hashes
+// to messageHash and their applied tags match expectedTags too. Discord
+// threads created via ForumThreadStartComplex have a starter message whose
+// ID equals the thread's own ID. Both lookups run through
+// executeWithCircuitBreaker like every other Discord call here: this is the
+// one path that runs precisely when Discord is failing transiently, so a
+// bare, unretried lookup error would make executeIdempotent fall through to
+// create() and produce a duplicate instead of recovering the prior attempt.
+func findRecentForumPost(ctx context.Context, providerCtx *Context, channelID, name, messageHash string, expectedTags []string) (id string, createdAt time.Time, found bool, err error) {
+	client := providerCtx.Session
+
+	threads, err := executeWithCircuitBreaker(ctx, providerCtx.CircuitBreaker, func() (*discordgo.ThreadsList, error) {
+		return client.ThreadsActive(channelID, discordgo.WithContext(ctx))
+	})
+	if err != nil {
+		return "", time.Time{}, false, err
+	}
+
+	for _, thread := range threads.Threads {
+		if thread.ParentID != channelID || thread.Name != name {
+			continue
+		}
+
+		threadCreatedAt, err := discordgo.SnowflakeTimestamp(thread.ID)
+		if err != nil || time.Since(threadCreatedAt) > idempotencyWindow {
+			continue
+		}
+
+		if !tagsMatch(thread.AppliedTags, expectedTags) {
+			continue
+		}
+
+		starter, err := executeWithCircuitBreaker(ctx, providerCtx.CircuitBreaker, func() (*discordgo.Message, error) {
+			return client.ChannelMessage(thread.ID, thread.ID, discordgo.WithContext(ctx))
+		})
+		if err != nil || hashForumPostMessage(starter.Content) != messageHash {
+			continue
+		}
+
+		return thread.ID, threadCreatedAt, true, nil
+	}
+
+	return "", time.Time{}, false, nil
+}
+
 func resourceForumPostRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*Context).Session
+	providerCtx := m.(*Context)
+	client := providerCtx.Session
 	var diags diag.Diagnostics
 
-	thread, err := executeWithRetry(ctx, func() (*discordgo.Channel, error) {
+	thread, err := executeWithCircuitBreaker(ctx, providerCtx.CircuitBreaker, func() (*discordgo.Channel, error) {
 		return client.Channel(d.Id(), discordgo.WithContext(ctx))
 	})
 
 	if err != nil {
-		// Check if the thread was deleted (404)
-		if restErr, ok := err.(*discordgo.RESTError); ok {
-			if restErr.Response != nil && restErr.Response.StatusCode == 404 {
-				d.SetId("")
-				return diags
-			}
+		// The thread was deleted out of band.
+		if errors.Is(err, ErrNotFound) {
+			d.SetId("")
+			return diags
 		}
 		return diag.Errorf("Failed to fetch forum post %s: %s", d.Id(), err.Error())
 	}
@@ -163,7 +240,8 @@ func resourceForumPostRead(ctx context.Context, d *schema.ResourceData, m interf
 }
 
 func resourceForumPostUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*Context).Session
+	providerCtx := m.(*Context)
+	client := providerCtx.Session
 
 	edit := &discordgo.ChannelEdit{}
 	hasChanges := false
@@ -203,7 +281,7 @@ func resourceForumPostUpdate(ctx context.Context, d *schema.ResourceData, m inte
 	}
 
 	if hasChanges {
-		err := executeWithRetryNoResult(ctx, func() error {
+		err := executeWithCircuitBreakerNoResult(ctx, providerCtx.CircuitBreaker, func() error {
 			_, err := client.ChannelEditComplex(d.Id(), edit, discordgo.WithContext(ctx))
 			return err
 		})
@@ -216,19 +294,18 @@ func resourceForumPostUpdate(ctx context.Context, d *schema.ResourceData, m inte
 }
 
 func resourceForumPostDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*Context).Session
+	providerCtx := m.(*Context)
+	client := providerCtx.Session
 	var diags diag.Diagnostics
 
-	_, err := executeWithRetry(ctx, func() (*discordgo.Channel, error) {
+	_, err := executeWithCircuitBreaker(ctx, providerCtx.CircuitBreaker, func() (*discordgo.Channel, error) {
 		return client.ChannelDelete(d.Id(), discordgo.WithContext(ctx))
 	})
 
 	if err != nil {
-		// Ignore 404 errors (already deleted)
-		if restErr, ok := err.(*discordgo.RESTError); ok {
-			if restErr.Response != nil && restErr.Response.StatusCode == 404 {
-				return diags
-			}
+		// Already deleted.
+		if errors.Is(err, ErrNotFound) {
+			return diags
 		}
 		return diag.Errorf("Failed to delete forum post: %s", err.Error())
 	}
@@ -0,0 +1,486 @@
+package discord
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// defaultForumPostsParallelism bounds how many posts a discord_forum_posts
+// worker pool creates at once when the config omits "parallelism". It's
+// below Terraform's own default resource parallelism (10) on the assumption
+// that a single bulk resource is usually one of several being applied
+// concurrently.
+const defaultForumPostsParallelism = 5
+
+func resourceDiscordForumPosts() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceForumPostsCreate,
+		ReadContext:   resourceForumPostsRead,
+		UpdateContext: resourceForumPostsUpdate,
+		DeleteContext: resourceForumPostsDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Description: "A resource to bulk-create forum posts (threads) in forum channels, with bounded concurrency and resumable partial progress.",
+
+		Schema: map[string]*schema.Schema{
+			"parallelism": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultForumPostsParallelism,
+				Description:  "Maximum number of posts to create concurrently.",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"posts": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "The forum posts to create, in order.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"channel_id": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The forum channel ID to create the post in.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The name/title of the forum post.",
+						},
+						"message": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The initial message content of the post.",
+						},
+						"auto_archive_duration": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      10080,
+							Description:  "Duration in minutes to auto-archive the thread (60, 1440, 4320, 10080).",
+							ValidateFunc: validation.IntInSlice([]int{60, 1440, 4320, 10080}),
+						},
+						"applied_tags": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "List of tag IDs to apply to the post.",
+						},
+						"pinned": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Whether the post is pinned in the forum.",
+						},
+					},
+				},
+			},
+			// Computed attributes
+			"created_ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The thread ID created for each entry in posts, in the same order. Empty string for an entry not yet created.",
+			},
+			"failed_indexes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "Indexes into posts that failed to create on the last apply and will be retried on the next one.",
+			},
+			"post_keys": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Internal identity key (derived from channel_id+name) recorded for each entry in created_ids, so a later insert/remove/reorder in posts is matched by identity rather than by position.",
+			},
+		},
+	}
+}
+
+// forumPostSpec is one entry of the posts list, decoded from schema.ResourceData.
+type forumPostSpec struct {
+	channelID           string
+	name                string
+	message             string
+	autoArchiveDuration int
+	appliedTags         []string
+	pinned              bool
+}
+
+func readForumPostSpecs(d *schema.ResourceData) []forumPostSpec {
+	raw := d.Get("posts").([]interface{})
+	specs := make([]forumPostSpec, len(raw))
+	for i, v := range raw {
+		item := v.(map[string]interface{})
+
+		var tags []string
+		for _, tag := range item["applied_tags"].([]interface{}) {
+			tags = append(tags, tag.(string))
+		}
+
+		specs[i] = forumPostSpec{
+			channelID:           item["channel_id"].(string),
+			name:                item["name"].(string),
+			message:             item["message"].(string),
+			autoArchiveDuration: item["auto_archive_duration"].(int),
+			appliedTags:         tags,
+			pinned:              item["pinned"].(bool),
+		}
+	}
+	return specs
+}
+
+// forumPostsResourceID deterministically derives the resource's own ID from
+// the channel/name of every post it manages, since the bulk resource has no
+// single underlying Discord object of its own.
+func forumPostsResourceID(specs []forumPostSpec) string {
+	parts := make([]string, len(specs))
+	for i, spec := range specs {
+		parts[i] = spec.channelID + "\x00" + spec.name
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x01")))
+	return hex.EncodeToString(sum[:])
+}
+
+// forumPostSpecKey derives spec's identity key from the fields that make it
+// "the same post" across applies (its channel and name), independent of its
+// position in posts. reconcileForumPostIDs uses this to match created_ids
+// entries by identity instead of by index.
+func forumPostSpecKey(spec forumPostSpec) string {
+	sum := sha256.Sum256([]byte(spec.channelID + "\x00" + spec.name))
+	return hex.EncodeToString(sum[:])
+}
+
+func forumPostSpecKeys(specs []forumPostSpec) []string {
+	keys := make([]string, len(specs))
+	for i, spec := range specs {
+		keys[i] = forumPostSpecKey(spec)
+	}
+	return keys
+}
+
+// reconcileForumPostIDs realigns prevCreatedIDs/prevFailedIndexes (recorded
+// against prevKeys, the post_keys of the previous apply) onto specs' current
+// order. Matching by identity rather than bare index means an insert,
+// removal, or reorder of an entry in posts doesn't cause a different post's
+// thread ID to be misattributed to it. Any previously-created thread whose
+// identity no longer appears in specs is returned in orphanedIDs so the
+// caller can clean it up instead of leaving it untracked.
+func reconcileForumPostIDs(specs []forumPostSpec, prevKeys, prevCreatedIDs []string, prevFailedIndexes []int) (createdIDs []string, retryIndexes []int, orphanedIDs []string) {
+	prevFailed := make(map[int]bool, len(prevFailedIndexes))
+	for _, i := range prevFailedIndexes {
+		prevFailed[i] = true
+	}
+
+	used := make([]bool, len(prevKeys))
+	createdIDs = make([]string, len(specs))
+
+	for i, key := range forumPostSpecKeys(specs) {
+		for j, prevKey := range prevKeys {
+			if used[j] || prevKey != key {
+				continue
+			}
+			used[j] = true
+			if j < len(prevCreatedIDs) {
+				createdIDs[i] = prevCreatedIDs[j]
+			}
+			if prevFailed[j] {
+				retryIndexes = append(retryIndexes, i)
+			}
+			break
+		}
+	}
+
+	for j := range prevKeys {
+		if !used[j] && j < len(prevCreatedIDs) && prevCreatedIDs[j] != "" {
+			orphanedIDs = append(orphanedIDs, prevCreatedIDs[j])
+		}
+	}
+
+	return createdIDs, retryIndexes, orphanedIDs
+}
+
+// runForumPostsBatch creates every post in specs that isn't already present
+// in createdIDs, plus any index listed in prevFailedIndexes (a post can have
+// a thread ID and still be failed, e.g. when its pin edit failed), using a
+// worker pool bounded by parallelism. Workers share providerCtx.Session, so
+// they share its rate limiter and circuit breaker with every other resource
+// in the run.
+func runForumPostsBatch(ctx context.Context, providerCtx *Context, specs []forumPostSpec, parallelism int, createdIDs []string, prevFailedIndexes []int) ([]string, []int) {
+	return runForumPostsBatchWith(ctx, specs, parallelism, createdIDs, prevFailedIndexes, func(spec forumPostSpec) (string, error) {
+		return createForumPost(ctx, providerCtx, spec)
+	})
+}
+
+// runForumPostsBatchWith is runForumPostsBatch with the per-post create call
+// injected, so the worker-pool/partial-result bookkeeping can be exercised
+// without a live Discord session.
+func runForumPostsBatchWith(ctx context.Context, specs []forumPostSpec, parallelism int, createdIDs []string, prevFailedIndexes []int, create func(forumPostSpec) (string, error)) ([]string, []int) {
+	for len(createdIDs) < len(specs) {
+		createdIDs = append(createdIDs, "")
+	}
+
+	retry := make(map[int]bool, len(prevFailedIndexes))
+	for _, i := range prevFailedIndexes {
+		retry[i] = true
+	}
+
+	pending := make([]int, 0, len(specs))
+	for i := range specs {
+		if createdIDs[i] == "" || retry[i] {
+			pending = append(pending, i)
+		}
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		jobs   = make(chan int)
+		failed = make(map[int]bool)
+	)
+
+	worker := func() {
+		defer wg.Done()
+		for i := range jobs {
+			threadID, err := create(specs[i])
+
+			mu.Lock()
+			// The thread itself may have been created even if err is set
+			// (e.g. the pin edit afterwards failed), so always keep a
+			// non-empty ID: otherwise a retry would duplicate the thread,
+			// and a destroy before the next apply would orphan it.
+			if threadID != "" {
+				createdIDs[i] = threadID
+			}
+			if err != nil {
+				failed[i] = true
+			}
+			mu.Unlock()
+		}
+	}
+
+	workers := parallelism
+	if workers > len(pending) {
+		workers = len(pending)
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go worker()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, i := range pending {
+			jobs <- i
+		}
+	}()
+	wg.Wait()
+
+	var stillFailed []int
+	for i := range specs {
+		if createdIDs[i] == "" || failed[i] {
+			stillFailed = append(stillFailed, i)
+		}
+	}
+
+	return createdIDs, stillFailed
+}
+
+// createForumPost creates a single forum post with the same retry,
+// idempotency and circuit-breaker handling as the singular
+// discord_forum_post resource.
+func createForumPost(ctx context.Context, providerCtx *Context, spec forumPostSpec) (string, error) {
+	client := providerCtx.Session
+
+	policy := providerCtx.BackoffPolicy
+	policy.MaxElapsedTime = 2 * time.Minute
+
+	messageHash := hashForumPostMessage(spec.message)
+
+	threadID, _, _, err := executeIdempotent(
+		ctx,
+		func(ctx context.Context) (string, time.Time, bool, error) {
+			return findRecentForumPost(ctx, providerCtx, spec.channelID, spec.name, messageHash, spec.appliedTags)
+		},
+		func() (*discordgo.Channel, error) {
+			return executeWithCircuitBreaker(ctx, providerCtx.CircuitBreaker, func() (*discordgo.Channel, error) {
+				return client.ForumThreadStartComplex(spec.channelID, &discordgo.ThreadStart{
+					Name:                spec.name,
+					AutoArchiveDuration: spec.autoArchiveDuration,
+					AppliedTags:         spec.appliedTags,
+				}, &discordgo.MessageSend{
+					Content: spec.message,
+				}, discordgo.WithContext(ctx))
+			}, WithBackoffPolicy(policy))
+		},
+		func(thread *discordgo.Channel) string { return thread.ID },
+	)
+	if err != nil {
+		return "", err
+	}
+
+	if spec.pinned {
+		flags := discordgo.ChannelFlagPinned
+		err := executeWithCircuitBreakerNoResult(ctx, providerCtx.CircuitBreaker, func() error {
+			_, err := client.ChannelEditComplex(threadID, &discordgo.ChannelEdit{
+				Flags: &flags,
+			}, discordgo.WithContext(ctx))
+			return err
+		})
+		if err != nil {
+			return threadID, err
+		}
+	}
+
+	return threadID, nil
+}
+
+func resourceForumPostsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	providerCtx := m.(*Context)
+	specs := readForumPostSpecs(d)
+	parallelism := d.Get("parallelism").(int)
+
+	d.SetId(forumPostsResourceID(specs))
+
+	createdIDs, failedIndexes := runForumPostsBatch(ctx, providerCtx, specs, parallelism, nil, nil)
+
+	d.Set("created_ids", createdIDs)
+	d.Set("failed_indexes", failedIndexes)
+	d.Set("post_keys", forumPostSpecKeys(specs))
+
+	if len(failedIndexes) > 0 {
+		return diag.Errorf("Failed to create %d of %d forum posts (indexes %v); re-apply to resume from where it left off", len(failedIndexes), len(specs), failedIndexes)
+	}
+
+	return nil
+}
+
+func resourceForumPostsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	providerCtx := m.(*Context)
+	client := providerCtx.Session
+	var diags diag.Diagnostics
+
+	createdIDsRaw := d.Get("created_ids").([]interface{})
+	createdIDs := make([]string, len(createdIDsRaw))
+	for i, v := range createdIDsRaw {
+		createdIDs[i] = v.(string)
+	}
+
+	var failedIndexes []int
+	for i, id := range createdIDs {
+		if id == "" {
+			failedIndexes = append(failedIndexes, i)
+			continue
+		}
+
+		_, err := executeWithCircuitBreaker(ctx, providerCtx.CircuitBreaker, func() (*discordgo.Channel, error) {
+			return client.Channel(id, discordgo.WithContext(ctx))
+		})
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				createdIDs[i] = ""
+				failedIndexes = append(failedIndexes, i)
+				continue
+			}
+			return diag.Errorf("Failed to fetch forum post %s (index %d): %s", id, i, err.Error())
+		}
+	}
+
+	d.Set("created_ids", createdIDs)
+	d.Set("failed_indexes", failedIndexes)
+	d.Set("post_keys", forumPostSpecKeys(readForumPostSpecs(d)))
+
+	return diags
+}
+
+func resourceForumPostsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	providerCtx := m.(*Context)
+	client := providerCtx.Session
+	specs := readForumPostSpecs(d)
+	parallelism := d.Get("parallelism").(int)
+
+	prevCreatedIDsRaw := d.Get("created_ids").([]interface{})
+	prevCreatedIDs := make([]string, len(prevCreatedIDsRaw))
+	for i, v := range prevCreatedIDsRaw {
+		prevCreatedIDs[i] = v.(string)
+	}
+
+	prevKeysRaw := d.Get("post_keys").([]interface{})
+	prevKeys := make([]string, len(prevKeysRaw))
+	for i, v := range prevKeysRaw {
+		prevKeys[i] = v.(string)
+	}
+
+	prevFailedRaw := d.Get("failed_indexes").([]interface{})
+	prevFailedIndexes := make([]int, len(prevFailedRaw))
+	for i, v := range prevFailedRaw {
+		prevFailedIndexes[i] = v.(int)
+	}
+
+	// Realign state onto specs' current order by post identity (channel_id +
+	// name), not by bare index, so an insert/removal/reorder in posts can't
+	// cause a different post's thread ID to be silently adopted.
+	createdIDs, retryIndexes, orphanedIDs := reconcileForumPostIDs(specs, prevKeys, prevCreatedIDs, prevFailedIndexes)
+
+	var diags diag.Diagnostics
+	for _, orphanID := range orphanedIDs {
+		_, err := executeWithCircuitBreaker(ctx, providerCtx.CircuitBreaker, func() (*discordgo.Channel, error) {
+			return client.ChannelDelete(orphanID, discordgo.WithContext(ctx))
+		})
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Failed to delete orphaned forum post",
+				Detail:   fmt.Sprintf("Thread %s was created for a posts entry that no longer matches any entry in the current config, but deleting it failed: %s. It is no longer tracked by this resource.", orphanID, err.Error()),
+			})
+		}
+	}
+
+	createdIDs, failedIndexes := runForumPostsBatch(ctx, providerCtx, specs, parallelism, createdIDs, retryIndexes)
+
+	d.Set("created_ids", createdIDs)
+	d.Set("failed_indexes", failedIndexes)
+	d.Set("post_keys", forumPostSpecKeys(specs))
+
+	if len(failedIndexes) > 0 {
+		return append(diags, diag.Errorf("Failed to create %d of %d forum posts (indexes %v); re-apply to resume from where it left off", len(failedIndexes), len(specs), failedIndexes)...)
+	}
+
+	return diags
+}
+
+func resourceForumPostsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	providerCtx := m.(*Context)
+	client := providerCtx.Session
+	var diags diag.Diagnostics
+
+	createdIDsRaw := d.Get("created_ids").([]interface{})
+
+	for i, v := range createdIDsRaw {
+		id := v.(string)
+		if id == "" {
+			continue
+		}
+
+		_, err := executeWithCircuitBreaker(ctx, providerCtx.CircuitBreaker, func() (*discordgo.Channel, error) {
+			return client.ChannelDelete(id, discordgo.WithContext(ctx))
+		})
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return diag.Errorf("Failed to delete forum post %s (index %d): %s", id, i, err.Error())
+		}
+	}
+
+	return diags
+}
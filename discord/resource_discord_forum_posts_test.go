@@ -0,0 +1,188 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestForumPostsResourceID_Deterministic(t *testing.T) {
+	specs := []forumPostSpec{
+		{channelID: "1", name: "a"},
+		{channelID: "1", name: "b"},
+	}
+
+	if forumPostsResourceID(specs) != forumPostsResourceID(specs) {
+		t.Error("expected the same specs to produce the same resource ID")
+	}
+}
+
+func TestForumPostsResourceID_DiffersOnOrder(t *testing.T) {
+	a := []forumPostSpec{{channelID: "1", name: "a"}, {channelID: "1", name: "b"}}
+	b := []forumPostSpec{{channelID: "1", name: "b"}, {channelID: "1", name: "a"}}
+
+	if forumPostsResourceID(a) == forumPostsResourceID(b) {
+		t.Error("expected reordering posts to change the resource ID")
+	}
+}
+
+func TestRunForumPostsBatchWith_KeepsIDWhenPostSucceedsButPinFails(t *testing.T) {
+	specs := []forumPostSpec{{channelID: "1", name: "a", pinned: true}}
+	pinErr := errors.New("failed to pin")
+
+	createdIDs, failedIndexes := runForumPostsBatchWith(context.Background(), specs, 1, nil, nil, func(forumPostSpec) (string, error) {
+		return "thread-1", pinErr
+	})
+
+	if createdIDs[0] != "thread-1" {
+		t.Errorf("expected the created thread ID to survive a later pin failure, got %q", createdIDs[0])
+	}
+	if len(failedIndexes) != 1 || failedIndexes[0] != 0 {
+		t.Errorf("expected index 0 to still be reported failed, got %v", failedIndexes)
+	}
+}
+
+func TestRunForumPostsBatchWith_ResumeRetriesPrevFailedEvenWithID(t *testing.T) {
+	specs := []forumPostSpec{{channelID: "1", name: "a"}}
+	calls := 0
+
+	createdIDs, failedIndexes := runForumPostsBatchWith(context.Background(), specs, 1, []string{"thread-1"}, []int{0}, func(forumPostSpec) (string, error) {
+		calls++
+		return "thread-1", nil
+	})
+
+	if calls != 1 {
+		t.Errorf("expected the previously-failed index to be retried, got %d calls", calls)
+	}
+	if createdIDs[0] != "thread-1" {
+		t.Errorf("expected createdIDs to retain thread-1, got %q", createdIDs[0])
+	}
+	if len(failedIndexes) != 0 {
+		t.Errorf("expected no failures after a successful retry, got %v", failedIndexes)
+	}
+}
+
+func TestReconcileForumPostIDs_MatchesByIdentityAcrossReorder(t *testing.T) {
+	prevSpecs := []forumPostSpec{{channelID: "1", name: "a"}, {channelID: "1", name: "b"}}
+	prevKeys := forumPostSpecKeys(prevSpecs)
+	prevCreatedIDs := []string{"thread-a", "thread-b"}
+
+	// b then a: reversed order from the previous apply.
+	newSpecs := []forumPostSpec{{channelID: "1", name: "b"}, {channelID: "1", name: "a"}}
+
+	createdIDs, retryIndexes, orphanedIDs := reconcileForumPostIDs(newSpecs, prevKeys, prevCreatedIDs, nil)
+
+	if createdIDs[0] != "thread-b" || createdIDs[1] != "thread-a" {
+		t.Errorf("expected IDs to follow their post's identity across the reorder, got %v", createdIDs)
+	}
+	if len(retryIndexes) != 0 {
+		t.Errorf("expected no retries, got %v", retryIndexes)
+	}
+	if len(orphanedIDs) != 0 {
+		t.Errorf("expected no orphans when every identity still exists, got %v", orphanedIDs)
+	}
+}
+
+func TestReconcileForumPostIDs_RemovedEntryIsOrphaned(t *testing.T) {
+	prevSpecs := []forumPostSpec{{channelID: "1", name: "a"}, {channelID: "1", name: "b"}}
+	prevKeys := forumPostSpecKeys(prevSpecs)
+	prevCreatedIDs := []string{"thread-a", "thread-b"}
+
+	// "b" was removed from config.
+	newSpecs := []forumPostSpec{{channelID: "1", name: "a"}}
+
+	createdIDs, _, orphanedIDs := reconcileForumPostIDs(newSpecs, prevKeys, prevCreatedIDs, nil)
+
+	if createdIDs[0] != "thread-a" {
+		t.Errorf("expected the remaining post to keep its ID, got %v", createdIDs)
+	}
+	if len(orphanedIDs) != 1 || orphanedIDs[0] != "thread-b" {
+		t.Errorf("expected thread-b to be reported orphaned, got %v", orphanedIDs)
+	}
+}
+
+func TestReconcileForumPostIDs_InsertedEntryStartsFresh(t *testing.T) {
+	prevSpecs := []forumPostSpec{{channelID: "1", name: "a"}}
+	prevKeys := forumPostSpecKeys(prevSpecs)
+	prevCreatedIDs := []string{"thread-a"}
+
+	// A new post inserted before the existing one.
+	newSpecs := []forumPostSpec{{channelID: "1", name: "new"}, {channelID: "1", name: "a"}}
+
+	createdIDs, _, orphanedIDs := reconcileForumPostIDs(newSpecs, prevKeys, prevCreatedIDs, nil)
+
+	if createdIDs[0] != "" {
+		t.Errorf("expected the newly inserted post to have no ID yet, got %q", createdIDs[0])
+	}
+	if createdIDs[1] != "thread-a" {
+		t.Errorf("expected the existing post to keep its ID despite shifting position, got %v", createdIDs)
+	}
+	if len(orphanedIDs) != 0 {
+		t.Errorf("expected no orphans on a pure insert, got %v", orphanedIDs)
+	}
+}
+
+func TestReconcileForumPostIDs_CarriesPreviousFailureByIdentity(t *testing.T) {
+	prevSpecs := []forumPostSpec{{channelID: "1", name: "a"}, {channelID: "1", name: "b"}}
+	prevKeys := forumPostSpecKeys(prevSpecs)
+	prevCreatedIDs := []string{"thread-a", ""}
+
+	// Reordered, with "b" (index 1, previously failed) now first.
+	newSpecs := []forumPostSpec{{channelID: "1", name: "b"}, {channelID: "1", name: "a"}}
+
+	_, retryIndexes, _ := reconcileForumPostIDs(newSpecs, prevKeys, prevCreatedIDs, []int{1})
+
+	if len(retryIndexes) != 1 || retryIndexes[0] != 0 {
+		t.Errorf("expected the previously-failed post's new index (0) to be retried, got %v", retryIndexes)
+	}
+}
+
+func TestAccResourceDiscordForumPosts_basic(t *testing.T) {
+	testServerID := os.Getenv("DISCORD_TEST_SERVER_ID")
+	if testServerID == "" {
+		t.Skip("DISCORD_TEST_SERVER_ID envvar must be set for acceptance tests")
+	}
+	name := "discord_forum_posts.test"
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceDiscordForumPosts_basic(testServerID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "posts.#", "2"),
+					resource.TestCheckResourceAttr(name, "created_ids.#", "2"),
+					resource.TestCheckResourceAttr(name, "failed_indexes.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccResourceDiscordForumPosts_basic(serverID string) string {
+	return fmt.Sprintf(`
+resource "discord_forum_channel" "test" {
+  server_id = "%[1]s"
+  name      = "terraform-test-forum-bulk"
+}
+
+resource "discord_forum_posts" "test" {
+  parallelism = 2
+
+  posts {
+    channel_id = discord_forum_channel.test.id
+    name       = "Bulk Post 1"
+    message    = "This is bulk post 1"
+  }
+
+  posts {
+    channel_id = discord_forum_channel.test.id
+    name       = "Bulk Post 2"
+    message    = "This is bulk post 2"
+  }
+}`, serverID)
+}